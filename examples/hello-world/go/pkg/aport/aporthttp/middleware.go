@@ -0,0 +1,93 @@
+package aporthttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/aporthq/aport-integrations/examples/hello-world/go/pkg/aport"
+)
+
+// DecisionIDHeader is the response header Middleware sets to the
+// decision ID returned by the verify call, whether the request was
+// allowed or denied.
+const DecisionIDHeader = "X-APort-Decision-Id"
+
+type contextKey int
+
+const decisionContextKey contextKey = iota
+
+// DecisionFromContext returns the *aport.Decision that Middleware
+// attached to the request context, if any.
+func DecisionFromContext(ctx context.Context) (*aport.Decision, bool) {
+	d, ok := ctx.Value(decisionContextKey).(*aport.Decision)
+	return d, ok
+}
+
+// denialBody is the JSON body written when a policy denies a request.
+type denialBody struct {
+	Error      string   `json:"error"`
+	DecisionID string   `json:"decision_id"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+// Middleware returns net/http middleware that verifies every request
+// against policyID before invoking the wrapped handler. agentIDs
+// resolves which agent the request is acting as; extract builds the
+// verification context (e.g. request body fields, route params) from
+// the incoming request.
+//
+// On allow, the decision is attached to the request context (see
+// DecisionFromContext) and the decision ID is echoed in the
+// X-APort-Decision-Id response header. On deny, or if verification
+// itself fails, the middleware writes the response itself and does not
+// call the wrapped handler.
+func Middleware(client *aport.Client, policyID string, agentIDs AgentIDResolver, extract func(*http.Request) map[string]interface{}) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			agentID, err := agentIDs.ResolveAgentID(r)
+			if err != nil {
+				writeJSONError(w, http.StatusUnauthorized, "", err.Error())
+				return
+			}
+
+			var attrs map[string]interface{}
+			if extract != nil {
+				attrs = extract(r)
+			}
+
+			decision, err := client.Verify(r.Context(), policyID, agentID, attrs)
+			if err != nil {
+				writeJSONError(w, http.StatusBadGateway, "", "aport verification unavailable: "+err.Error())
+				return
+			}
+
+			w.Header().Set(DecisionIDHeader, decision.DecisionID)
+
+			if !decision.Allow {
+				reasons := make([]string, 0, len(decision.Reasons))
+				for _, reason := range decision.Reasons {
+					reasons = append(reasons, reason.Message)
+				}
+				writeJSONError(w, http.StatusForbidden, decision.DecisionID, "", reasons...)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), decisionContextKey, decision)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, status int, decisionID, errMsg string, reasons ...string) {
+	if errMsg == "" {
+		errMsg = "request denied by policy"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(denialBody{
+		Error:      errMsg,
+		DecisionID: decisionID,
+		Reasons:    reasons,
+	})
+}
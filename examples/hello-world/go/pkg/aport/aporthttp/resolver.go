@@ -0,0 +1,82 @@
+// Package aporthttp provides net/http middleware that enforces an
+// APort policy on incoming requests before they reach a handler.
+package aporthttp
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AgentIDResolver extracts the agent ID that a request is acting as, so
+// Middleware knows which agent to verify against the policy.
+type AgentIDResolver interface {
+	ResolveAgentID(r *http.Request) (string, error)
+}
+
+// AgentIDResolverFunc adapts a plain function to an AgentIDResolver.
+type AgentIDResolverFunc func(r *http.Request) (string, error)
+
+// ResolveAgentID calls f.
+func (f AgentIDResolverFunc) ResolveAgentID(r *http.Request) (string, error) {
+	return f(r)
+}
+
+// StaticAgentID returns a resolver that always yields the same agent
+// ID, for services that act as a single fixed agent.
+func StaticAgentID(agentID string) AgentIDResolver {
+	return AgentIDResolverFunc(func(*http.Request) (string, error) {
+		return agentID, nil
+	})
+}
+
+// JWTSubjectResolver resolves the agent ID from the `sub` claim of a
+// bearer JWT in the Authorization header, validated with keyFunc.
+func JWTSubjectResolver(keyFunc jwt.Keyfunc) AgentIDResolver {
+	return AgentIDResolverFunc(func(r *http.Request) (string, error) {
+		authz := r.Header.Get("Authorization")
+		const prefix = "Bearer "
+		if !strings.HasPrefix(authz, prefix) {
+			return "", fmt.Errorf("missing bearer token")
+		}
+		tokenString := strings.TrimPrefix(authz, prefix)
+
+		claims := jwt.MapClaims{}
+		if _, err := jwt.ParseWithClaims(tokenString, claims, keyFunc); err != nil {
+			return "", fmt.Errorf("invalid bearer token: %w", err)
+		}
+
+		sub, ok := claims["sub"].(string)
+		if !ok || sub == "" {
+			return "", fmt.Errorf("token has no sub claim")
+		}
+		return sub, nil
+	})
+}
+
+// SPIFFEResolver resolves the agent ID from the SPIFFE ID carried as a
+// URI SAN on the client certificate presented over mTLS.
+func SPIFFEResolver() AgentIDResolver {
+	return AgentIDResolverFunc(func(r *http.Request) (string, error) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return "", fmt.Errorf("no client certificate presented")
+		}
+		id, err := spiffeIDFromCert(r.TLS.PeerCertificates[0])
+		if err != nil {
+			return "", err
+		}
+		return id, nil
+	})
+}
+
+func spiffeIDFromCert(cert *x509.Certificate) (string, error) {
+	for _, uri := range cert.URIs {
+		if uri.Scheme == "spiffe" {
+			return uri.String(), nil
+		}
+	}
+	return "", fmt.Errorf("client certificate has no spiffe:// URI SAN")
+}
@@ -0,0 +1,75 @@
+package aport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aporthq/aport-integrations/examples/hello-world/go/pkg/aport/passport"
+)
+
+type assumeRequest struct {
+	Context VerifyContext `json:"context"`
+}
+
+type assumeResponse struct {
+	Token string `json:"token"`
+}
+
+// AssumeAgentRole calls /api/verify/assume, which behaves like Verify
+// but, on allow, returns a signed "agent passport" JWT instead of (or
+// alongside) a bare decision. The passport carries decision_id,
+// policy_id, agent_id and an expiry equal to the decision's ExpiresIn,
+// so a chain of internal RPCs can be authorized from one verification
+// round-trip by passing the passport along instead of calling Verify
+// again at every hop.
+func (c *Client) AssumeAgentRole(ctx context.Context, policyID, agentID string, attrs map[string]interface{}) (*passport.Passport, error) {
+	reqBody := assumeRequest{
+		Context: VerifyContext{
+			AgentID:  agentID,
+			PolicyID: policyID,
+			Context:  attrs,
+		},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/verify/assume", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result assumeResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.Token == "" {
+		return nil, fmt.Errorf("assume response has no token")
+	}
+
+	return passport.ParseUnverified(result.Token)
+}
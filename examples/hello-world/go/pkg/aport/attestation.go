@@ -0,0 +1,101 @@
+package aport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// attestationContextKey is the reserved key under which attestation
+// evidence and the measured agent binary hash are placed in a verify
+// context, so server-side policy can bind agent_id to a measured
+// identity instead of trusting it outright.
+const attestationContextKey = "attestation"
+
+// Attestor collects platform attestation evidence to bind a verify
+// request to the measured identity of the host it's running on. See
+// pkg/aport/attestor for implementations (TPM 2.0, AWS Nitro).
+//
+// challenge is a fresh, server-chosen nonce fetched immediately before
+// the call; implementations must bind it into the evidence (e.g. as
+// the TPM quote's qualifying data) rather than generate their own, or
+// a captured evidence blob could be replayed indefinitely.
+type Attestor interface {
+	Attest(ctx context.Context, challenge []byte) (map[string]interface{}, error)
+}
+
+// WithAttestor configures a Client to attach attestation evidence to
+// every verify request. With an Attestor configured, Verify bypasses
+// the decision cache, so no decision is served from it.
+func WithAttestor(a Attestor) Option {
+	return func(c *Client) { c.attestor = a }
+}
+
+var (
+	binarySHA256Once  sync.Once
+	binarySHA256Value string
+	binarySHA256Err   error
+)
+
+// binarySHA256 measures the running executable once and caches the
+// result, since re-hashing it on every verify call would be wasted
+// work: the binary doesn't change between requests.
+func binarySHA256() (string, error) {
+	binarySHA256Once.Do(func() {
+		path, err := os.Executable()
+		if err != nil {
+			binarySHA256Err = fmt.Errorf("resolve agent binary path: %w", err)
+			return
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			binarySHA256Err = fmt.Errorf("open agent binary: %w", err)
+			return
+		}
+		defer f.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			binarySHA256Err = fmt.Errorf("hash agent binary: %w", err)
+			return
+		}
+		binarySHA256Value = hex.EncodeToString(h.Sum(nil))
+	})
+	return binarySHA256Value, binarySHA256Err
+}
+
+// attest attaches attestation evidence and the measured agent binary
+// hash to attrs under attestationContextKey, leaving attrs untouched
+// when no Attestor is configured.
+func (c *Client) attest(ctx context.Context, attrs map[string]interface{}) (map[string]interface{}, error) {
+	if c.attestor == nil {
+		return attrs, nil
+	}
+
+	challenge, err := c.fetchChallenge(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch attestation challenge: %w", err)
+	}
+
+	evidence, err := c.attestor.Attest(ctx, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("collect attestation evidence: %w", err)
+	}
+
+	hash, err := binarySHA256()
+	if err != nil {
+		return nil, fmt.Errorf("measure agent binary: %w", err)
+	}
+	evidence["agent_binary_sha256"] = hash
+
+	merged := make(map[string]interface{}, len(attrs)+1)
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	merged[attestationContextKey] = evidence
+	return merged, nil
+}
@@ -0,0 +1,64 @@
+package attestor
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hf/nsm"
+	"github.com/hf/nsm/request"
+)
+
+// NitroAttestor requests an AWS Nitro Enclave attestation document
+// from the enclave's Nitro Secure Module (NSM), binding a verify
+// request to the enclave's measured PCRs.
+type NitroAttestor struct {
+	userData []byte
+}
+
+// NewNitroAttestor creates a NitroAttestor. userData, if given, is
+// embedded in the attestation document's user_data field only when
+// Attest is called with an empty challenge; a non-empty challenge
+// takes its place.
+func NewNitroAttestor(userData ...[]byte) *NitroAttestor {
+	a := &NitroAttestor{}
+	if len(userData) > 0 {
+		a.userData = userData[0]
+	}
+	return a
+}
+
+// Attest opens the enclave's NSM device and requests an attestation
+// document, returning it base64-encoded in the evidence map. challenge,
+// the server-chosen verify challenge, is embedded as the document's
+// user_data in preference to any userData configured on the
+// NitroAttestor, so the document is bound to this specific request
+// instead of being replayable across requests.
+func (a *NitroAttestor) Attest(ctx context.Context, challenge []byte) (map[string]interface{}, error) {
+	session, err := nsm.OpenDefaultSession()
+	if err != nil {
+		return nil, fmt.Errorf("open nsm session: %w", err)
+	}
+	defer session.Close()
+
+	userData := a.userData
+	if len(challenge) > 0 {
+		userData = challenge
+	}
+
+	res, err := session.Send(&request.Attestation{UserData: userData})
+	if err != nil {
+		return nil, fmt.Errorf("nsm attestation request: %w", err)
+	}
+	if res.Error != "" {
+		return nil, fmt.Errorf("nsm attestation request: %s", res.Error)
+	}
+	if res.Attestation == nil || res.Attestation.Document == nil {
+		return nil, fmt.Errorf("nsm returned no attestation document")
+	}
+
+	return map[string]interface{}{
+		"type":     "aws-nitro",
+		"document": base64.StdEncoding.EncodeToString(res.Attestation.Document),
+	}, nil
+}
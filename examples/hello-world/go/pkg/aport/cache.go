@@ -0,0 +1,194 @@
+package aport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// refreshThreshold is how much of an entry's TTL may elapse before a
+// background refresh is kicked off, expressed as a fraction remaining.
+// 0.20 means "refresh once 80% of the TTL has passed".
+const refreshThreshold = 0.20
+
+type cacheEntry struct {
+	policyID   string
+	agentID    string
+	decision   *Decision
+	cachedAt   time.Time
+	expiresAt  time.Time
+	refreshing bool
+}
+
+// cacheStats receives hit/miss/eviction notifications from a
+// decisionCache. Every field may be nil; WithMetrics is the only
+// producer today.
+type cacheStats struct {
+	onHit   func()
+	onMiss  func()
+	onEvict func()
+}
+
+// decisionCache is an in-process, TTL-based cache of verification
+// decisions keyed on (policy_id, agent_id, hash(context)). It behaves
+// like a blocking-query cache: reads never block on the network once an
+// entry exists, and entries nearing expiry are refreshed in the
+// background rather than evicted outright.
+type decisionCache struct {
+	ttl   time.Duration
+	stats cacheStats
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+func newDecisionCache(ttl time.Duration) *decisionCache {
+	return &decisionCache{
+		ttl:     ttl,
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// get returns the cached decision for key if it hasn't expired.
+func (c *decisionCache) get(key string) (*Decision, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		c.notify(c.stats.onMiss)
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(c.entries, key)
+		c.notify(c.stats.onEvict)
+		c.notify(c.stats.onMiss)
+		return nil, false
+	}
+	c.notify(c.stats.onHit)
+	return e.decision, true
+}
+
+func (c *decisionCache) notify(f func()) {
+	if f != nil {
+		f()
+	}
+}
+
+// set stores d under key with a TTL derived from d.ExpiresIn, falling
+// back to the cache's configured TTL when the server didn't send one.
+func (c *decisionCache) set(key, policyID, agentID string, d *Decision) {
+	ttl := c.ttl
+	if d.ExpiresIn > 0 {
+		ttl = time.Duration(d.ExpiresIn) * time.Second
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = &cacheEntry{
+		policyID:  policyID,
+		agentID:   agentID,
+		decision:  d,
+		cachedAt:  now,
+		expiresAt: now.Add(ttl),
+	}
+}
+
+// snapshot returns a point-in-time copy of every live cache entry, for
+// the /debug/aport handler.
+func (c *decisionCache) snapshot() []CacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]CacheEntry, 0, len(c.entries))
+	for key, e := range c.entries {
+		out = append(out, CacheEntry{
+			Key:        key,
+			PolicyID:   e.policyID,
+			AgentID:    e.agentID,
+			Allow:      e.decision.Allow,
+			DecisionID: e.decision.DecisionID,
+			CachedAt:   e.cachedAt,
+			ExpiresAt:  e.expiresAt,
+		})
+	}
+	return out
+}
+
+// needsRefresh reports whether the entry for key is within
+// refreshThreshold of expiring and isn't already being refreshed.
+func (c *decisionCache) needsRefresh(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || e.refreshing {
+		return false
+	}
+	total := e.expiresAt.Sub(e.cachedAt)
+	if total <= 0 {
+		return false
+	}
+	remaining := time.Until(e.expiresAt)
+	return remaining <= time.Duration(float64(total)*refreshThreshold)
+}
+
+// startRefresh marks key as being refreshed, returning false if a
+// refresh is already in flight so callers don't launch duplicates.
+func (c *decisionCache) startRefresh(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || e.refreshing {
+		return false
+	}
+	e.refreshing = true
+	return true
+}
+
+func (c *decisionCache) finishRefresh(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.refreshing = false
+	}
+}
+
+// cacheKeyFor derives a stable cache key from (policy_id, agent_id,
+// hash(context)), matching attrs regardless of map iteration order.
+func cacheKeyFor(policyID, agentID string, attrs map[string]interface{}) string {
+	return policyID + "|" + agentID + "|" + hashContext(attrs)
+}
+
+// hashContext produces a stable SHA-256 digest of attrs by sorting keys
+// before marshaling, so equivalent contexts always hash identically.
+func hashContext(attrs map[string]interface{}) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	ordered := make([]interface{}, 0, len(keys)*2)
+	for _, k := range keys {
+		ordered = append(ordered, k, attrs[k])
+	}
+
+	b, err := json.Marshal(ordered)
+	if err != nil {
+		// attrs should always be JSON-marshalable request context; if
+		// it isn't, fall back to a key that never matches the cache.
+		return "unhashable"
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
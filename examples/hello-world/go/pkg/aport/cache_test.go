@@ -0,0 +1,153 @@
+package aport
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDecisionCacheSetUsesExpiresInOverConfiguredTTL(t *testing.T) {
+	c := newDecisionCache(time.Hour)
+	c.set("k", "policy", "agent", &Decision{Allow: true, ExpiresIn: 1})
+
+	c.mu.Lock()
+	e := c.entries["k"]
+	c.mu.Unlock()
+
+	if got := e.expiresAt.Sub(e.cachedAt); got != time.Second {
+		t.Fatalf("expiresAt-cachedAt = %v, want 1s (from ExpiresIn)", got)
+	}
+}
+
+func TestDecisionCacheSetFallsBackToConfiguredTTL(t *testing.T) {
+	c := newDecisionCache(30 * time.Second)
+	c.set("k", "policy", "agent", &Decision{Allow: true})
+
+	c.mu.Lock()
+	e := c.entries["k"]
+	c.mu.Unlock()
+
+	if got := e.expiresAt.Sub(e.cachedAt); got != 30*time.Second {
+		t.Fatalf("expiresAt-cachedAt = %v, want 30s (fallback TTL)", got)
+	}
+}
+
+func TestDecisionCacheSetWithNoTTLDoesNotCache(t *testing.T) {
+	c := newDecisionCache(0)
+	c.set("k", "policy", "agent", &Decision{Allow: true})
+
+	if _, ok := c.get("k"); ok {
+		t.Fatal("get returned a decision for an entry that should never have been cached")
+	}
+}
+
+func TestDecisionCacheGetEvictsExpiredEntries(t *testing.T) {
+	c := newDecisionCache(time.Millisecond)
+	c.set("k", "policy", "agent", &Decision{Allow: true})
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.get("k"); ok {
+		t.Fatal("get returned a decision past its expiry")
+	}
+
+	c.mu.Lock()
+	_, present := c.entries["k"]
+	c.mu.Unlock()
+	if present {
+		t.Fatal("expired entry was not evicted from the map")
+	}
+}
+
+func TestDecisionCacheNeedsRefresh(t *testing.T) {
+	c := newDecisionCache(0)
+	now := time.Now()
+
+	c.mu.Lock()
+	c.entries["fresh"] = &cacheEntry{cachedAt: now, expiresAt: now.Add(time.Hour)}
+	c.entries["stale"] = &cacheEntry{cachedAt: now.Add(-80 * time.Minute), expiresAt: now.Add(20 * time.Minute)}
+	c.mu.Unlock()
+
+	if c.needsRefresh("fresh") {
+		t.Error("fresh entry (well within TTL) should not need a refresh")
+	}
+	if !c.needsRefresh("stale") {
+		t.Error("stale entry (80% of TTL elapsed) should need a refresh")
+	}
+	if c.needsRefresh("missing") {
+		t.Error("missing key should not need a refresh")
+	}
+}
+
+func TestDecisionCacheStartRefreshPreventsDuplicates(t *testing.T) {
+	c := newDecisionCache(0)
+	now := time.Now()
+	c.mu.Lock()
+	c.entries["k"] = &cacheEntry{cachedAt: now, expiresAt: now.Add(time.Hour)}
+	c.mu.Unlock()
+
+	if !c.startRefresh("k") {
+		t.Fatal("first startRefresh should succeed")
+	}
+	if c.startRefresh("k") {
+		t.Fatal("second concurrent startRefresh should be rejected while one is in flight")
+	}
+
+	c.finishRefresh("k")
+
+	if !c.startRefresh("k") {
+		t.Fatal("startRefresh should succeed again once finishRefresh clears the flag")
+	}
+}
+
+// TestDecisionCacheStartRefreshIsRaceFree exercises startRefresh from many
+// goroutines at once: exactly one caller must win, since refreshAsync
+// relies on this to avoid launching duplicate background refreshes for
+// the same key. Run with -race to catch any missing synchronization.
+func TestDecisionCacheStartRefreshIsRaceFree(t *testing.T) {
+	c := newDecisionCache(0)
+	now := time.Now()
+	c.mu.Lock()
+	c.entries["k"] = &cacheEntry{cachedAt: now, expiresAt: now.Add(time.Hour)}
+	c.mu.Unlock()
+
+	const n = 50
+	var wg sync.WaitGroup
+	var wins int32
+	var mu sync.Mutex
+
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			if c.startRefresh("k") {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if wins != 1 {
+		t.Fatalf("startRefresh let %d callers win concurrently, want exactly 1", wins)
+	}
+}
+
+func TestCacheKeyForIsStableAcrossMapOrder(t *testing.T) {
+	a := map[string]interface{}{"a": 1, "b": "two"}
+	b := map[string]interface{}{"b": "two", "a": 1}
+
+	if cacheKeyFor("p", "agent", a) != cacheKeyFor("p", "agent", b) {
+		t.Fatal("cacheKeyFor should not depend on map iteration order")
+	}
+}
+
+func TestCacheKeyForDiffersOnContext(t *testing.T) {
+	a := map[string]interface{}{"a": 1}
+	b := map[string]interface{}{"a": 2}
+
+	if cacheKeyFor("p", "agent", a) == cacheKeyFor("p", "agent", b) {
+		t.Fatal("cacheKeyFor should differ when context differs")
+	}
+}
@@ -0,0 +1,52 @@
+package aport
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type challengeResponse struct {
+	Nonce string `json:"nonce"`
+}
+
+// fetchChallenge asks the server for a fresh, server-chosen attestation
+// challenge. Remote attestation's anti-replay guarantee depends on the
+// verifier picking the nonce, so this must happen before every
+// Attestor.Attest call rather than the attestor minting its own.
+func (c *Client) fetchChallenge(ctx context.Context) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/verify/challenge", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch attestation challenge: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read challenge response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("challenge endpoint returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result challengeResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse challenge response: %w", err)
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(result.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("challenge nonce is not valid base64: %w", err)
+	}
+	return nonce, nil
+}
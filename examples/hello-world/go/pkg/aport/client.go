@@ -0,0 +1,307 @@
+// Package aport is a small Go client for the APort verification API
+// (https://aport.io). It wraps the POST /api/verify/policy/{policy}
+// call shown in the hello-world example with a decision cache that
+// makes it safe to call Verify on every request of a high-QPS sidecar.
+package aport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultBaseURL is the default APort API base URL.
+	DefaultBaseURL = "https://aport.io"
+
+	defaultUserAgent = "aport-go-client/1.0"
+)
+
+// Mode selects whether Client.Verify calls the remote APort API or a
+// local LocalEvaluator.
+type Mode string
+
+const (
+	// ModeRemote verifies against the remote APort API. This is the
+	// default; if a LocalEvaluator is configured, a failed remote call
+	// falls back to it automatically.
+	ModeRemote Mode = "remote"
+
+	// ModeLocal evaluates every request against the configured
+	// LocalEvaluator and never calls the remote API.
+	ModeLocal Mode = "local"
+)
+
+// LocalEvaluator evaluates a verification context without calling the
+// remote APort API, for disconnected or offline operation.
+type LocalEvaluator interface {
+	Evaluate(ctx context.Context, vc VerifyContext) (*Decision, error)
+}
+
+// Client talks to the APort verify API and caches decisions so that
+// repeated verifications for the same agent/policy/context don't incur
+// a round trip on every call.
+type Client struct {
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
+	mode       Mode
+	local      LocalEvaluator
+	attestor   Attestor
+	metrics    *clientMetrics
+	tracer     trace.Tracer
+	logger     *slog.Logger
+
+	cache   *decisionCache
+	denials *denialLog
+	sf      singleflight.Group
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient overrides the http.Client used for verify requests.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithUserAgent overrides the User-Agent header sent with verify requests.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithCache replaces the default in-process decision cache. ttl is the
+// fallback TTL for decisions whose ExpiresIn is not positive; with
+// ttl <= 0, only decisions carrying a positive ExpiresIn are cached
+// (as with the default cache). Safe to combine
+// with WithMetrics in either order: WithCache carries over any
+// hit/miss/eviction counters already wired onto the Client's cache, so
+// metrics keep reporting against the new cache either way.
+func WithCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		stats := c.cache.stats
+		c.cache = newDecisionCache(ttl)
+		c.cache.stats = stats
+	}
+}
+
+// WithMode overrides the verification mode. By default NewClient reads
+// APORT_MODE from the environment ("local" or "remote"), defaulting to
+// ModeRemote.
+func WithMode(mode Mode) Option {
+	return func(c *Client) { c.mode = mode }
+}
+
+// WithLocalEvaluator configures a fallback (or, under ModeLocal,
+// primary) evaluator used when the remote API is unreachable or
+// bypassed.
+func WithLocalEvaluator(e LocalEvaluator) Option {
+	return func(c *Client) { c.local = e }
+}
+
+// NewClient creates a Client for the given APort base URL (for example
+// "https://aport.io"). baseURL must not have a trailing slash.
+func NewClient(baseURL string, opts ...Option) *Client {
+	mode := ModeRemote
+	if Mode(os.Getenv("APORT_MODE")) == ModeLocal {
+		mode = ModeLocal
+	}
+
+	c := &Client{
+		baseURL:    baseURL,
+		userAgent:  defaultUserAgent,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		mode:       mode,
+		tracer:     trace.NewNoopTracerProvider().Tracer("aport"),
+		cache:      newDecisionCache(0),
+		denials:    newDenialLog(defaultDenialLogSize),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Verify checks whether agentID is allowed to act under policyID given
+// attrs, returning the cached decision when one is still fresh.
+//
+// Concurrent calls for the same (policyID, agentID, attrs) are
+// collapsed into a single upstream request via singleflight, and once
+// cached, entries within 20% of their ExpiresIn are refreshed in the
+// background so callers keep reading a warm cache instead of blocking
+// on expiry.
+//
+// When a WithAttestor is configured, the decision cache is bypassed
+// entirely: a cached Allow=true would let any caller who guesses the
+// same (policyID, agentID, attrs) reuse a decision that was only ever
+// attested for the original caller's host, defeating the point of
+// attestation.
+func (c *Client) Verify(ctx context.Context, policyID, agentID string, attrs map[string]interface{}) (*Decision, error) {
+	start := time.Now()
+
+	if c.attestor != nil {
+		d, err := c.resolve(ctx, policyID, agentID, attrs)
+		c.recordDecision(ctx, policyID, agentID, attrs, d, err, start)
+		return d, err
+	}
+
+	key := cacheKeyFor(policyID, agentID, attrs)
+
+	if d, ok := c.cache.get(key); ok {
+		if c.cache.needsRefresh(key) {
+			c.refreshAsync(key, policyID, agentID, attrs)
+		}
+		c.recordDecision(ctx, policyID, agentID, attrs, d, nil, start)
+		return d, nil
+	}
+
+	v, err, _ := c.sf.Do(key, func() (interface{}, error) {
+		return c.resolve(ctx, policyID, agentID, attrs)
+	})
+	if err != nil {
+		c.recordDecision(ctx, policyID, agentID, attrs, nil, err, start)
+		return nil, err
+	}
+
+	d := v.(*Decision)
+	c.cache.set(key, policyID, agentID, d)
+	c.recordDecision(ctx, policyID, agentID, attrs, d, nil, start)
+	return d, nil
+}
+
+// resolve picks the remote or local evaluation path according to the
+// client's mode, falling back from remote to local on error when a
+// LocalEvaluator is configured.
+func (c *Client) resolve(ctx context.Context, policyID, agentID string, attrs map[string]interface{}) (*Decision, error) {
+	attrs, err := c.attest(ctx, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.mode == ModeLocal {
+		return c.evaluateLocal(ctx, policyID, agentID, attrs)
+	}
+
+	d, err := c.verify(ctx, policyID, agentID, attrs)
+	if err != nil && c.local != nil {
+		return c.evaluateLocal(ctx, policyID, agentID, attrs)
+	}
+	return d, err
+}
+
+// evaluateLocal runs the configured LocalEvaluator, which produces a
+// Decision with the same JSON shape as the remote API.
+func (c *Client) evaluateLocal(ctx context.Context, policyID, agentID string, attrs map[string]interface{}) (*Decision, error) {
+	if c.local == nil {
+		return nil, fmt.Errorf("aport: no local evaluator configured")
+	}
+	return c.local.Evaluate(ctx, VerifyContext{
+		AgentID:  agentID,
+		PolicyID: policyID,
+		Context:  attrs,
+	})
+}
+
+// refreshAsync re-verifies key in the background and updates the cache
+// on success. Failures are left in place; the stale entry keeps serving
+// until it actually expires.
+func (c *Client) refreshAsync(key, policyID, agentID string, attrs map[string]interface{}) {
+	if !c.cache.startRefresh(key) {
+		return
+	}
+	go func() {
+		defer c.cache.finishRefresh(key)
+		v, err, _ := c.sf.Do(key, func() (interface{}, error) {
+			return c.resolve(context.Background(), policyID, agentID, attrs)
+		})
+		if err != nil {
+			return
+		}
+		c.cache.set(key, policyID, agentID, v.(*Decision))
+	}()
+}
+
+// verify performs the uncached POST to /api/verify/policy/{policy},
+// wrapped in an OpenTelemetry span so the round trip shows up in
+// distributed traces alongside the rest of the request.
+func (c *Client) verify(ctx context.Context, policyID, agentID string, attrs map[string]interface{}) (*Decision, error) {
+	ctx, span := c.tracer.Start(ctx, "aport.verify")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("aport.policy_id", policyID),
+		attribute.String("aport.agent_id", agentID),
+	)
+
+	decision, err := c.doVerify(ctx, policyID, agentID, attrs)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("aport.decision_id", decision.DecisionID),
+		attribute.Bool("aport.allow", decision.Allow),
+	)
+	return decision, nil
+}
+
+// doVerify performs the uncached POST to /api/verify/policy/{policy}.
+func (c *Client) doVerify(ctx context.Context, policyID, agentID string, attrs map[string]interface{}) (*Decision, error) {
+	reqBody := VerifyRequest{
+		Context: VerifyContext{
+			AgentID:  agentID,
+			PolicyID: policyID,
+			Context:  attrs,
+		},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/verify/policy/%s", c.baseURL, policyID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result VerifyResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	decision := result.decision()
+	if decision == nil {
+		return nil, fmt.Errorf("no decision found in response")
+	}
+	return decision, nil
+}
@@ -0,0 +1,32 @@
+// Package debug provides an HTTP handler, modeled on Consul's agent
+// debug endpoints, that dumps a Client's live state for operators
+// troubleshooting a denied agent in production.
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aporthq/aport-integrations/examples/hello-world/go/pkg/aport"
+)
+
+// dump is the JSON body written by Handler.
+type dump struct {
+	PolicyBundleVersion string               `json:"policy_bundle_version,omitempty"`
+	Cache               []aport.CacheEntry   `json:"cache"`
+	RecentDenials       []aport.DenialRecord `json:"recent_denials"`
+}
+
+// Handler returns an http.Handler suitable for mounting at
+// "/debug/aport" that reports client's decision-cache contents, most
+// recent denials, and current policy bundle version as JSON.
+func Handler(client *aport.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(dump{
+			PolicyBundleVersion: client.PolicyBundleVersion(),
+			Cache:               client.CacheSnapshot(),
+			RecentDenials:       client.RecentDenials(),
+		})
+	})
+}
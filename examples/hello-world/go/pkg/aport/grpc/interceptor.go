@@ -0,0 +1,121 @@
+// Package grpc provides unary and stream gRPC interceptors that
+// enforce an APort policy on incoming RPCs, mirroring the net/http
+// middleware in pkg/aport/aporthttp.
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/aporthq/aport-integrations/examples/hello-world/go/pkg/aport"
+)
+
+// DecisionIDMetadataKey is the outgoing header set to the decision ID
+// returned by the verify call, mirroring aporthttp.DecisionIDHeader.
+const DecisionIDMetadataKey = "x-aport-decision-id"
+
+type contextKey int
+
+const decisionContextKey contextKey = iota
+
+// DecisionFromContext returns the *aport.Decision attached to ctx by
+// the interceptors, if any.
+func DecisionFromContext(ctx context.Context) (*aport.Decision, bool) {
+	d, ok := ctx.Value(decisionContextKey).(*aport.Decision)
+	return d, ok
+}
+
+// AgentIDResolver extracts the agent ID an RPC is acting as from its
+// context (e.g. from a peer's TLS certificate or an auth header).
+type AgentIDResolver interface {
+	ResolveAgentID(ctx context.Context) (string, error)
+}
+
+// AgentIDResolverFunc adapts a plain function to an AgentIDResolver.
+type AgentIDResolverFunc func(ctx context.Context) (string, error)
+
+// ResolveAgentID calls f.
+func (f AgentIDResolverFunc) ResolveAgentID(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticAgentID returns a resolver that always yields the same agent ID.
+func StaticAgentID(agentID string) AgentIDResolver {
+	return AgentIDResolverFunc(func(context.Context) (string, error) {
+		return agentID, nil
+	})
+}
+
+// ExtractFunc builds a verification context from an RPC request and
+// its context, analogous to aporthttp's extract callback.
+type ExtractFunc func(ctx context.Context, req interface{}) map[string]interface{}
+
+func verify(ctx context.Context, client *aport.Client, policyID string, agentIDs AgentIDResolver, extract ExtractFunc, req interface{}) (context.Context, error) {
+	agentID, err := agentIDs.ResolveAgentID(ctx)
+	if err != nil {
+		return ctx, status.Errorf(codes.Unauthenticated, "resolve agent id: %v", err)
+	}
+
+	var attrs map[string]interface{}
+	if extract != nil {
+		attrs = extract(ctx, req)
+	}
+
+	decision, err := client.Verify(ctx, policyID, agentID, attrs)
+	if err != nil {
+		return ctx, status.Errorf(codes.Unavailable, "aport verification unavailable: %v", err)
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(DecisionIDMetadataKey, decision.DecisionID))
+
+	if !decision.Allow {
+		reasons := ""
+		for i, reason := range decision.Reasons {
+			if i > 0 {
+				reasons += "; "
+			}
+			reasons += reason.Message
+		}
+		return ctx, status.Errorf(codes.PermissionDenied, "denied by policy %s: %s", policyID, reasons)
+	}
+
+	return context.WithValue(ctx, decisionContextKey, decision), nil
+}
+
+// UnaryServerInterceptor verifies every unary RPC against policyID
+// before invoking the handler.
+func UnaryServerInterceptor(client *aport.Client, policyID string, agentIDs AgentIDResolver, extract ExtractFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := verify(ctx, client, policyID, agentIDs, extract, req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// serverStreamWithContext overrides ServerStream.Context so the
+// verified decision is visible to the handler via Context().
+type serverStreamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStreamWithContext) Context() context.Context { return s.ctx }
+
+// StreamServerInterceptor verifies every streaming RPC against
+// policyID before invoking the handler. extract receives a nil req, as
+// stream messages arrive after the call is already established.
+func StreamServerInterceptor(client *aport.Client, policyID string, agentIDs AgentIDResolver, extract ExtractFunc) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := verify(ss.Context(), client, policyID, agentIDs, extract, nil)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,148 @@
+package local
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// BundleLoader periodically fetches a signed policy bundle from a URL
+// and hot-swaps it into a RegoEvaluator, so a long-running process
+// picks up policy changes without a restart.
+//
+// The bundle is a .tar.gz of one or more .rego files; the loader also
+// fetches "<url>.sig", an ed25519 signature over the raw .tar.gz bytes,
+// and refuses to swap in a bundle that doesn't verify against PublicKey.
+type BundleLoader struct {
+	URL          string
+	PublicKey    ed25519.PublicKey
+	PollInterval time.Duration
+	Evaluator    *RegoEvaluator
+
+	httpClient *http.Client
+}
+
+// NewBundleLoader creates a loader for the signed bundle at url, which
+// will be polled every pollInterval and hot-swapped into evaluator.
+func NewBundleLoader(url string, publicKey ed25519.PublicKey, pollInterval time.Duration, evaluator *RegoEvaluator) *BundleLoader {
+	return &BundleLoader{
+		URL:          url,
+		PublicKey:    publicKey,
+		PollInterval: pollInterval,
+		Evaluator:    evaluator,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Run fetches the bundle once immediately, then again every
+// PollInterval until ctx is canceled. It returns the error from the
+// first fetch so callers can fail fast on startup misconfiguration;
+// errors from subsequent polls are returned to onError if non-nil.
+func (l *BundleLoader) Run(ctx context.Context, onError func(error)) error {
+	if err := l.fetchAndSwap(ctx); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(l.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := l.fetchAndSwap(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (l *BundleLoader) fetchAndSwap(ctx context.Context) error {
+	bundleBytes, err := l.get(ctx, l.URL)
+	if err != nil {
+		return fmt.Errorf("fetch bundle: %w", err)
+	}
+
+	sig, err := l.get(ctx, l.URL+".sig")
+	if err != nil {
+		return fmt.Errorf("fetch bundle signature: %w", err)
+	}
+
+	if !ed25519.Verify(l.PublicKey, bundleBytes, sig) {
+		return fmt.Errorf("bundle signature verification failed")
+	}
+
+	modules, err := extractRego(bundleBytes)
+	if err != nil {
+		return fmt.Errorf("extract bundle: %w", err)
+	}
+
+	if err := l.Evaluator.Load(ctx, modules); err != nil {
+		return fmt.Errorf("compile bundle: %w", err)
+	}
+	return nil
+}
+
+func (l *BundleLoader) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// extractRego reads every *.rego file out of a .tar.gz bundle, keyed by
+// its path within the archive. Each file is compiled as its own
+// rego.Module rather than concatenated into one, since a bundle
+// commonly ships several files that all declare `package aport`, which
+// would fail to parse as a single module.
+func extractRego(bundleBytes []byte) (map[string]string, error) {
+	gz, err := gzip.NewReader(strings.NewReader(string(bundleBytes)))
+	if err != nil {
+		return nil, fmt.Errorf("gunzip bundle: %w", err)
+	}
+	defer gz.Close()
+
+	modules := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read tar entry: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Ext(hdr.Name) != ".rego" {
+			continue
+		}
+		var sb strings.Builder
+		if _, err := io.Copy(&sb, tr); err != nil {
+			return nil, fmt.Errorf("read %s: %w", hdr.Name, err)
+		}
+		modules[hdr.Name] = sb.String()
+	}
+
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("bundle contains no .rego files")
+	}
+	return modules, nil
+}
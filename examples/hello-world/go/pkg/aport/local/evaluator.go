@@ -0,0 +1,180 @@
+// Package local provides an offline aport.LocalEvaluator backed by OPA
+// Rego, for services that need to keep enforcing policy when the
+// remote APort API is unreachable (APORT_MODE=local, or as an
+// automatic fallback from Client.Verify).
+package local
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/open-policy-agent/opa/rego"
+
+	"github.com/aporthq/aport-integrations/examples/hello-world/go/pkg/aport"
+)
+
+// defaultExpiresInSeconds is used for decisions synthesized locally,
+// since there is no server to propose a TTL.
+const defaultExpiresInSeconds = 60
+
+// RegoEvaluator evaluates verification contexts against a compiled
+// Rego policy whose `deny[msg]` rules determine the decision: allow is
+// true when the deny set is empty, and each deny message becomes a
+// Decision reason.
+//
+// The compiled query can be hot-swapped via Load, so a RegoEvaluator
+// can keep running while a BundleLoader replaces its policy in the
+// background.
+type RegoEvaluator struct {
+	mu      sync.RWMutex
+	query   rego.PreparedEvalQuery
+	version string
+}
+
+// NewRegoEvaluator compiles modules (one or more Rego policy sources,
+// package aport, with `deny[msg]` rules, keyed by filename) into a
+// RegoEvaluator.
+func NewRegoEvaluator(ctx context.Context, modules map[string]string) (*RegoEvaluator, error) {
+	e := &RegoEvaluator{}
+	if err := e.Load(ctx, modules); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func compile(ctx context.Context, modules map[string]string) (rego.PreparedEvalQuery, error) {
+	opts := []func(*rego.Rego){rego.Query("data.aport.deny")}
+	for _, name := range sortedKeys(modules) {
+		opts = append(opts, rego.Module(name, modules[name]))
+	}
+	r := rego.New(opts...)
+	return r.PrepareForEval(ctx)
+}
+
+// Load compiles modules and atomically swaps them in as the
+// evaluator's active policy, e.g. after a BundleLoader fetches a new
+// bundle. Each entry is compiled as its own rego.Module so a policy
+// split across several files (each declaring `package aport`) doesn't
+// need merging.
+func (e *RegoEvaluator) Load(ctx context.Context, modules map[string]string) error {
+	query, err := compile(ctx, modules)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.query = query
+	e.version = modulesVersion(modules)
+	return nil
+}
+
+func sortedKeys(modules map[string]string) []string {
+	keys := make([]string, 0, len(modules))
+	for name := range modules {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Version returns a short, deterministic identifier for the currently
+// loaded policy module, implementing aport.VersionedEvaluator.
+func (e *RegoEvaluator) Version() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.version
+}
+
+// modulesVersion deterministically derives a version identifier from
+// every module's content, ordered by filename so the result doesn't
+// depend on map iteration order.
+func modulesVersion(modules map[string]string) string {
+	h := sha256.New()
+	for _, name := range sortedKeys(modules) {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(modules[name]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Evaluate implements aport.LocalEvaluator.
+func (e *RegoEvaluator) Evaluate(ctx context.Context, vc aport.VerifyContext) (*aport.Decision, error) {
+	e.mu.RLock()
+	query := e.query
+	e.mu.RUnlock()
+
+	rs, err := query.Eval(ctx, rego.EvalInput(map[string]interface{}{
+		"agent_id":  vc.AgentID,
+		"policy_id": vc.PolicyID,
+		"context":   vc.Context,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("rego evaluation failed: %w", err)
+	}
+
+	var reasons []aport.Reason
+	for _, result := range rs {
+		for _, expr := range result.Expressions {
+			msgs, ok := expr.Value.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, msg := range msgs {
+				if s, ok := msg.(string); ok {
+					reasons = append(reasons, aport.Reason{Message: s})
+				}
+			}
+		}
+	}
+
+	return &aport.Decision{
+		Allow:      len(reasons) == 0,
+		DecisionID: synthesizeDecisionID(vc),
+		ExpiresIn:  defaultExpiresInSeconds,
+		Reasons:    reasons,
+	}, nil
+}
+
+// synthesizeDecisionID deterministically derives a decision ID from
+// the verification context, since there is no server to mint one. It
+// hashes vc.Context as well as the policy/agent IDs, so two decisions
+// for the same agent/policy (e.g. a $1 refund vs. a $1,000,000 refund)
+// get distinct IDs instead of colliding.
+func synthesizeDecisionID(vc aport.VerifyContext) string {
+	h := sha256.New()
+	h.Write([]byte(vc.PolicyID + "|" + vc.AgentID + "|"))
+	h.Write([]byte(hashContext(vc.Context)))
+	return "local_" + hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// hashContext produces a stable SHA-256 digest of attrs by sorting
+// keys before marshaling, so equivalent contexts always hash
+// identically regardless of map iteration order.
+func hashContext(attrs map[string]interface{}) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	ordered := make([]interface{}, 0, len(keys)*2)
+	for _, k := range keys {
+		ordered = append(ordered, k, attrs[k])
+	}
+
+	b, err := json.Marshal(ordered)
+	if err != nil {
+		return "unhashable"
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
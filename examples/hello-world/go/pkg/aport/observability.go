@@ -0,0 +1,217 @@
+package aport
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultDenialLogSize bounds how many recent denials Client keeps in
+// memory for the /debug/aport handler.
+const defaultDenialLogSize = 50
+
+// clientMetrics holds the Prometheus collectors registered by
+// WithMetrics.
+type clientMetrics struct {
+	requestsTotal  *prometheus.CounterVec
+	latency        *prometheus.HistogramVec
+	cacheHits      prometheus.Counter
+	cacheMisses    prometheus.Counter
+	cacheEvictions prometheus.Counter
+}
+
+func newClientMetrics(reg prometheus.Registerer) *clientMetrics {
+	m := &clientMetrics{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aport_verify_requests_total",
+			Help: "Total number of APort verify calls, by policy, agent, and outcome.",
+		}, []string{"policy", "agent", "decision"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "aport_verify_latency_seconds",
+			Help:    "Latency of APort verify calls, including cache hits.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"policy"}),
+		cacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "aport_decision_cache_hits_total",
+			Help: "Total number of decision cache hits.",
+		}),
+		cacheMisses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "aport_decision_cache_misses_total",
+			Help: "Total number of decision cache misses.",
+		}),
+		cacheEvictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "aport_decision_cache_evictions_total",
+			Help: "Total number of decision cache entries evicted for expiry.",
+		}),
+	}
+	reg.MustRegister(m.requestsTotal, m.latency, m.cacheHits, m.cacheMisses, m.cacheEvictions)
+	return m
+}
+
+// WithMetrics registers Prometheus collectors for the verification
+// path against reg: aport_verify_requests_total{policy,agent,decision},
+// aport_verify_latency_seconds, and
+// aport_decision_cache_{hits,misses,evictions}_total.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(c *Client) {
+		c.metrics = newClientMetrics(reg)
+		c.cache.stats = cacheStats{
+			onHit:   c.metrics.cacheHits.Inc,
+			onMiss:  c.metrics.cacheMisses.Inc,
+			onEvict: c.metrics.cacheEvictions.Inc,
+		}
+	}
+}
+
+// WithTracer wraps each remote verify HTTP call in a span from tracer,
+// tagged with aport.policy_id, aport.agent_id, aport.decision_id, and
+// aport.allow.
+func WithTracer(tracer trace.Tracer) Option {
+	return func(c *Client) { c.tracer = tracer }
+}
+
+// WithLogger emits one structured log record per decision (via
+// slog.Logger), including the decision and the request's context keys
+// (values are redacted; only key names are logged).
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) { c.logger = logger }
+}
+
+// recordDecision updates metrics, logs, and the denial log for a single
+// Verify call. err is non-nil only when verification itself failed
+// (neither allowed nor denied).
+func (c *Client) recordDecision(ctx context.Context, policyID, agentID string, attrs map[string]interface{}, d *Decision, err error, start time.Time) {
+	latency := time.Since(start)
+
+	outcome := "error"
+	if d != nil {
+		if d.Allow {
+			outcome = "allow"
+		} else {
+			outcome = "deny"
+		}
+	}
+
+	if c.metrics != nil {
+		c.metrics.requestsTotal.WithLabelValues(policyID, agentID, outcome).Inc()
+		c.metrics.latency.WithLabelValues(policyID).Observe(latency.Seconds())
+	}
+
+	if c.logger != nil {
+		c.logger.LogAttrs(ctx, slog.LevelInfo, "aport decision",
+			slog.String("policy_id", policyID),
+			slog.String("agent_id", agentID),
+			slog.String("outcome", outcome),
+			slog.Duration("latency", latency),
+			slog.Any("context_keys", contextKeys(attrs)),
+		)
+	}
+
+	if d != nil && !d.Allow {
+		c.denials.add(DenialRecord{
+			PolicyID:   policyID,
+			AgentID:    agentID,
+			DecisionID: d.DecisionID,
+			Reasons:    reasonMessages(d.Reasons),
+			At:         start,
+		})
+	}
+}
+
+func contextKeys(attrs map[string]interface{}) []string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func reasonMessages(reasons []Reason) []string {
+	out := make([]string, 0, len(reasons))
+	for _, r := range reasons {
+		out = append(out, r.Message)
+	}
+	return out
+}
+
+// DenialRecord is a single denied decision, as surfaced by
+// Client.RecentDenials and the /debug/aport handler.
+type DenialRecord struct {
+	PolicyID   string
+	AgentID    string
+	DecisionID string
+	Reasons    []string
+	At         time.Time
+}
+
+// denialLog is a fixed-size ring buffer of the most recent denials.
+type denialLog struct {
+	mu      sync.Mutex
+	entries []DenialRecord
+	size    int
+}
+
+func newDenialLog(size int) *denialLog {
+	return &denialLog{size: size}
+}
+
+func (d *denialLog) add(r DenialRecord) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries = append(d.entries, r)
+	if len(d.entries) > d.size {
+		d.entries = d.entries[len(d.entries)-d.size:]
+	}
+}
+
+func (d *denialLog) snapshot() []DenialRecord {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]DenialRecord, len(d.entries))
+	copy(out, d.entries)
+	return out
+}
+
+// CacheEntry is a point-in-time view of one decision-cache entry, as
+// surfaced by Client.CacheSnapshot and the /debug/aport handler.
+type CacheEntry struct {
+	Key        string
+	PolicyID   string
+	AgentID    string
+	Allow      bool
+	DecisionID string
+	CachedAt   time.Time
+	ExpiresAt  time.Time
+}
+
+// CacheSnapshot returns every live entry in the decision cache.
+func (c *Client) CacheSnapshot() []CacheEntry {
+	return c.cache.snapshot()
+}
+
+// RecentDenials returns the most recent denied decisions, oldest first.
+func (c *Client) RecentDenials() []DenialRecord {
+	return c.denials.snapshot()
+}
+
+// VersionedEvaluator is a LocalEvaluator that can report the version of
+// the policy bundle it's currently evaluating against, e.g. a
+// local.RegoEvaluator kept fresh by a local.BundleLoader.
+type VersionedEvaluator interface {
+	LocalEvaluator
+	Version() string
+}
+
+// PolicyBundleVersion returns the configured LocalEvaluator's bundle
+// version, or "" if none is configured or it doesn't implement
+// VersionedEvaluator.
+func (c *Client) PolicyBundleVersion() string {
+	if v, ok := c.local.(VersionedEvaluator); ok {
+		return v.Version()
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+package passport
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultJWKSTTL bounds how long a fetched key set is trusted before a
+// Keyfunc lookup forces a refresh, independent of an unknown-kid miss.
+const defaultJWKSTTL = 10 * time.Minute
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwksDocument struct {
+	Keys []jwk `json:"keys"`
+}
+
+// JWKS fetches and caches the public keys published at a JWKS URL,
+// refreshing them when an unknown key ID is seen or the cached set goes
+// stale.
+type JWKS struct {
+	url        string
+	ttl        time.Duration
+	httpClient *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWKS creates a JWKS fetcher for baseURL + "/.well-known/jwks.json".
+func NewJWKS(baseURL string) *JWKS {
+	return &JWKS{
+		url:        baseURL + "/.well-known/jwks.json",
+		ttl:        defaultJWKSTTL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		keys:       make(map[string]crypto.PublicKey),
+	}
+}
+
+// Keyfunc implements jwt.Keyfunc, resolving the public key for the
+// token's `kid` header, refreshing the cached key set on a miss or once
+// it's gone stale.
+func (j *JWKS) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, fmt.Errorf("token has no kid header")
+	}
+
+	if key, ok := j.key(kid); ok {
+		return key, nil
+	}
+	if err := j.refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	key, ok := j.key(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+func (j *JWKS) key(kid string) (crypto.PublicKey, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if time.Since(j.fetchedAt) > j.ttl {
+		return nil, false
+	}
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+func (j *JWKS) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch jwks: status %d", resp.StatusCode)
+	}
+
+	var doc jwksDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		pub, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.fetchedAt = time.Now()
+	j.mu.Unlock()
+	return nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64urlBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %q: bad modulus: %w", k.Kid, err)
+		}
+		eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %q: bad exponent: %w", k.Kid, err)
+		}
+		e := new(big.Int).SetBytes(eBytes)
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		curve, err := curveForName(k.Crv)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %q: %w", k.Kid, err)
+		}
+		x, err := base64urlBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %q: bad x: %w", k.Kid, err)
+		}
+		y, err := base64urlBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %q: bad y: %w", k.Kid, err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("jwk %q: unsupported kty %q", k.Kid, k.Kty)
+	}
+}
+
+func base64urlBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func curveForName(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", name)
+	}
+}
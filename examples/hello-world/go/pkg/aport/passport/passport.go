@@ -0,0 +1,56 @@
+// Package passport implements "agent passports": compact, signed JWTs
+// minted by APort's /api/verify/assume endpoint that let a chain of
+// internal RPCs be authorized from a single verification round-trip,
+// instead of every hop calling back to /verify.
+package passport
+
+import (
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the JWT claims carried by an agent passport.
+type Claims struct {
+	DecisionID  string `json:"decision_id"`
+	PolicyID    string `json:"policy_id"`
+	AgentID     string `json:"agent_id"`
+	ContextHash string `json:"context_hash"`
+	jwt.RegisteredClaims
+}
+
+// Passport is a parsed agent passport: the raw compact JWT plus its
+// claims.
+type Passport struct {
+	Raw    string
+	Claims Claims
+}
+
+// ParseUnverified decodes tokenString's claims without checking its
+// signature. Client.AssumeAgentRole uses this because the token was
+// just received directly from APort over TLS; services receiving a
+// passport from elsewhere should use Verify instead.
+func ParseUnverified(tokenString string) (*Passport, error) {
+	var claims Claims
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, &claims); err != nil {
+		return nil, fmt.Errorf("parse passport: %w", err)
+	}
+	return &Passport{Raw: tokenString, Claims: claims}, nil
+}
+
+// Verify checks tokenString's signature against jwks (fetched from
+// {BaseURL}/.well-known/jwks.json) and validates standard claims,
+// returning the parsed Passport on success. Supports RS256 and ES256
+// signed passports.
+func Verify(tokenString string, jwks *JWKS) (*Passport, error) {
+	var claims Claims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, jwks.Keyfunc,
+		jwt.WithValidMethods([]string{"RS256", "ES256"}))
+	if err != nil {
+		return nil, fmt.Errorf("verify passport: %w", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("verify passport: invalid token")
+	}
+	return &Passport{Raw: tokenString, Claims: claims}, nil
+}
@@ -0,0 +1,46 @@
+package aport
+
+// VerifyRequest represents the request payload for APort verification.
+type VerifyRequest struct {
+	Context VerifyContext `json:"context"`
+}
+
+// VerifyContext contains the verification context.
+type VerifyContext struct {
+	AgentID  string                 `json:"agent_id"`
+	PolicyID string                 `json:"policy_id"`
+	Context  map[string]interface{} `json:"context"`
+}
+
+// VerifyResponse represents the response from APort verification.
+type VerifyResponse struct {
+	Data     *DecisionData `json:"data,omitempty"`
+	Decision *Decision     `json:"decision,omitempty"`
+}
+
+// DecisionData wraps the decision object.
+type DecisionData struct {
+	Decision *Decision `json:"decision,omitempty"`
+}
+
+// Decision represents the verification decision.
+type Decision struct {
+	Allow      bool     `json:"allow"`
+	DecisionID string   `json:"decision_id"`
+	ExpiresIn  int      `json:"expires_in"`
+	Reasons    []Reason `json:"reasons,omitempty"`
+}
+
+// Reason represents a decision reason.
+type Reason struct {
+	Message string `json:"message"`
+}
+
+// decision unwraps a VerifyResponse, tolerating both the flat and
+// data-wrapped shapes the API has returned historically.
+func (r *VerifyResponse) decision() *Decision {
+	if r.Data != nil && r.Data.Decision != nil {
+		return r.Data.Decision
+	}
+	return r.Decision
+}